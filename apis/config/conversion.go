@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+// modeToScoringStrategyType maps the deprecated Mode field to its
+// ScoringStrategyType equivalent.
+var modeToScoringStrategyType = map[ModeType]ScoringStrategyType{
+	Least:    LeastAllocated,
+	Most:     MostAllocated,
+	Balanced: BalancedAllocation,
+	Shape:    RequestedToCapacityRatio,
+}
+
+// scoringStrategyTypeToMode is the inverse of modeToScoringStrategyType.
+var scoringStrategyTypeToMode = map[ScoringStrategyType]ModeType{
+	LeastAllocated:           Least,
+	MostAllocated:            Most,
+	BalancedAllocation:       Balanced,
+	RequestedToCapacityRatio: Shape,
+}
+
+// ModeForScoringStrategyType returns the deprecated ModeType equivalent of a
+// ScoringStrategyType, for the internal scorers that have not been migrated
+// to switch on ScoringStrategyType directly.
+func ModeForScoringStrategyType(t ScoringStrategyType) ModeType {
+	return scoringStrategyTypeToMode[t]
+}
+
+// ConvertToScoringStrategy builds a ScoringStrategy from the deprecated
+// Mode, Resources and Shape fields of args, for callers that have not
+// migrated to ScoringStrategy yet. args.Mode must already be non-empty.
+func ConvertToScoringStrategy(args *NodeResourcesAllocatableArgs) *ScoringStrategy {
+	strategy := &ScoringStrategy{
+		Type:      modeToScoringStrategyType[args.Mode],
+		Resources: args.Resources,
+	}
+	if args.Mode == Shape {
+		strategy.RequestedToCapacityRatio = &RequestedToCapacityRatioParam{Shape: args.Shape}
+	}
+	return strategy
+}
+
+// ConvertToDeprecatedArgs populates the deprecated Mode, Resources and Shape
+// fields of a NodeResourcesAllocatableArgs from a ScoringStrategy, for code
+// paths that still read the old fields.
+func ConvertToDeprecatedArgs(strategy *ScoringStrategy) *NodeResourcesAllocatableArgs {
+	args := &NodeResourcesAllocatableArgs{
+		Mode:      ModeForScoringStrategyType(strategy.Type),
+		Resources: strategy.Resources,
+	}
+	if strategy.RequestedToCapacityRatio != nil {
+		args.Shape = strategy.RequestedToCapacityRatio.Shape
+	}
+	return args
+}
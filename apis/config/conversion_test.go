@@ -0,0 +1,79 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	schedulerconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
+)
+
+func TestConvertToScoringStrategy(t *testing.T) {
+	resources := []schedulerconfig.ResourceSpec{{Name: "cpu", Weight: 1}}
+	shape := []UtilizationShapePoint{{Utilization: 0, Score: 10}, {Utilization: 100, Score: 0}}
+
+	tests := []struct {
+		name string
+		args *NodeResourcesAllocatableArgs
+		want *ScoringStrategy
+	}{
+		{
+			name: "least",
+			args: &NodeResourcesAllocatableArgs{Mode: Least, Resources: resources},
+			want: &ScoringStrategy{Type: LeastAllocated, Resources: resources},
+		},
+		{
+			name: "most",
+			args: &NodeResourcesAllocatableArgs{Mode: Most, Resources: resources},
+			want: &ScoringStrategy{Type: MostAllocated, Resources: resources},
+		},
+		{
+			name: "balanced",
+			args: &NodeResourcesAllocatableArgs{Mode: Balanced, Resources: resources},
+			want: &ScoringStrategy{Type: BalancedAllocation, Resources: resources},
+		},
+		{
+			name: "shape",
+			args: &NodeResourcesAllocatableArgs{Mode: Shape, Resources: resources, Shape: shape},
+			want: &ScoringStrategy{
+				Type:                     RequestedToCapacityRatio,
+				Resources:                resources,
+				RequestedToCapacityRatio: &RequestedToCapacityRatioParam{Shape: shape},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ConvertToScoringStrategy(tt.args)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ConvertToScoringStrategy(%+v) = %+v, want %+v", tt.args, got, tt.want)
+			}
+
+			// Round-tripping through ConvertToDeprecatedArgs should reproduce
+			// the original deprecated fields.
+			roundTripped := ConvertToDeprecatedArgs(got)
+			if roundTripped.Mode != tt.args.Mode {
+				t.Errorf("ConvertToDeprecatedArgs(...).Mode = %v, want %v", roundTripped.Mode, tt.args.Mode)
+			}
+			if !reflect.DeepEqual(roundTripped.Shape, tt.args.Shape) {
+				t.Errorf("ConvertToDeprecatedArgs(...).Shape = %v, want %v", roundTripped.Shape, tt.args.Shape)
+			}
+		})
+	}
+}
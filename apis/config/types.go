@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	schedulerconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
+)
+
+// ModeType is a "string" type.
+type ModeType string
+
+const (
+	// Least is the mode which favors the node with the least allocatable resources.
+	Least ModeType = "Least"
+	// Most is the mode which favors the node with the most allocatable resources.
+	Most ModeType = "Most"
+	// Shape lets the user define a piecewise-linear utility curve per resource,
+	// mapping a resource's utilization percentage to a score.
+	Shape ModeType = "Shape"
+	// Balanced favors nodes whose configured resources have the most evenly
+	// balanced requested-to-allocatable fractions, once the scheduled pod is
+	// accounted for.
+	Balanced ModeType = "Balanced"
+)
+
+// UtilizationShapePoint represents a single point of a utility curve, mapping a
+// resource's utilization percentage to a score. Utilization must be between 0
+// and 100, and Score must be between 0 and MaxNodeScore. Utilization
+// is always "percentage requested", matching upstream RequestedToCapacityRatio;
+// there is no knob to shape against headroom ("percentage free") instead —
+// invert the curve's Utilization points (100-u) to express that.
+type UtilizationShapePoint struct {
+	// Utilization is a value between 0 and 100.
+	Utilization int32
+	// Score is a value between 0 and MaxNodeScore.
+	Score int32
+}
+
+// ScoringStrategyType is the type of scoring strategy used in
+// ScoringStrategy.
+type ScoringStrategyType string
+
+const (
+	// LeastAllocated favors nodes with the least allocatable resources.
+	LeastAllocated ScoringStrategyType = "LeastAllocated"
+	// MostAllocated favors nodes with the most allocatable resources.
+	MostAllocated ScoringStrategyType = "MostAllocated"
+	// BalancedAllocation favors nodes whose configured resources have the
+	// most evenly balanced requested-to-allocatable fractions.
+	BalancedAllocation ScoringStrategyType = "BalancedAllocation"
+	// RequestedToCapacityRatio lets the user define a piecewise-linear
+	// utility curve per resource, mapping a resource's utilization
+	// percentage to a score.
+	RequestedToCapacityRatio ScoringStrategyType = "RequestedToCapacityRatio"
+)
+
+// RequestedToCapacityRatioParam holds the parameters for the
+// RequestedToCapacityRatio scoring strategy.
+type RequestedToCapacityRatioParam struct {
+	// Shape must be sorted by increasing Utilization and have at least one point.
+	Shape []UtilizationShapePoint
+}
+
+// ScoringStrategy defines the scoring strategy for the
+// NodeResourcesAllocatable plugin, mirroring the ScoringStrategy introduced
+// for the in-tree NodeResourcesFit plugin so that LeastAllocated,
+// MostAllocated, BalancedAllocation and RequestedToCapacityRatio can share a
+// single plugin registration and config path.
+type ScoringStrategy struct {
+	// Type selects which strategy to run. Defaults to LeastAllocated.
+	Type ScoringStrategyType
+
+	// Resources to be considered when scoring.
+	// The default resource set includes "cpu" and "memory", each with equal weight.
+	Resources []schedulerconfig.ResourceSpec
+
+	// RequestedToCapacityRatio is required if Type is RequestedToCapacityRatio,
+	// and must not be set otherwise.
+	RequestedToCapacityRatio *RequestedToCapacityRatioParam
+}
+
+// VolumeResourceSpec represents a storage class to be folded into scoring
+// alongside the node's compute resources.
+type VolumeResourceSpec struct {
+	// StorageClass is the name of the StorageClass whose CSI-reported
+	// capacity should be scored.
+	StorageClass string
+	// Weight of this storage class. Must be a positive value.
+	Weight int64
+}
+
+// NodeResourcesAllocatableArgs holds arguments used to configure the
+// NodeResourcesAllocatable plugin.
+type NodeResourcesAllocatableArgs struct {
+	metav1.TypeMeta
+
+	// ScoringStrategy selects the scoring strategy and its parameters.
+	// Mutually exclusive with Mode, Resources and Shape below.
+	ScoringStrategy *ScoringStrategy
+
+	// Volumes lists storage classes whose CSI-reported allocatable capacity
+	// should be folded into the same weighted average as Resources, using
+	// a PVC's requested size vs. the node's available capacity for its
+	// storage class. A class absent from a node's reported capacity (no CSI
+	// driver advertises it there) is skipped for that node rather than
+	// scored as 0.
+	Volumes []VolumeResourceSpec
+
+	// Deprecated: use ScoringStrategy.Resources instead.
+	// Resources to be considered when scoring.
+	// The default resource set includes "cpu" and "memory", each with equal weight.
+	Resources []schedulerconfig.ResourceSpec
+
+	// Deprecated: use ScoringStrategy.Type instead.
+	// Selects the scoring mode: Least, Most, Shape or Balanced.
+	Mode ModeType
+
+	// Deprecated: use ScoringStrategy.RequestedToCapacityRatio.Shape instead.
+	// Shape is the utility curve used when Mode is Shape. It must be sorted by
+	// increasing Utilization and have at least one point.
+	Shape []UtilizationShapePoint
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	schedulerconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+)
+
+// ValidateNodeResourcesAllocatableArgs validates that NodeResourcesAllocatableArgs are set correctly.
+func ValidateNodeResourcesAllocatableArgs(args *config.NodeResourcesAllocatableArgs, path *field.Path) error {
+	for _, volume := range args.Volumes {
+		if volume.Weight <= 0 {
+			return fmt.Errorf("volume Weight of %v should be a positive value, got %v", volume.StorageClass, volume.Weight)
+		}
+	}
+
+	if args.ScoringStrategy != nil {
+		if args.Mode != "" || len(args.Resources) != 0 || len(args.Shape) != 0 {
+			return fmt.Errorf("scoringStrategy cannot be combined with the deprecated mode, resources or shape fields")
+		}
+		return validateScoringStrategy(args.ScoringStrategy)
+	}
+
+	switch args.Mode {
+	case config.Least, config.Most, config.Balanced:
+		if len(args.Shape) != 0 {
+			return fmt.Errorf("shape should not be configured when mode is %q", args.Mode)
+		}
+	case config.Shape:
+		if err := validateShape(args.Shape); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid mode, got %q", args.Mode)
+	}
+
+	return validateResourceWeights(args.Resources)
+}
+
+// validateScoringStrategy validates a ScoringStrategy, the successor of the
+// deprecated Mode/Resources/Shape fields.
+func validateScoringStrategy(strategy *config.ScoringStrategy) error {
+	switch strategy.Type {
+	case config.LeastAllocated, config.MostAllocated, config.BalancedAllocation:
+		if strategy.RequestedToCapacityRatio != nil {
+			return fmt.Errorf("requestedToCapacityRatio should not be configured when type is %q", strategy.Type)
+		}
+	case config.RequestedToCapacityRatio:
+		if strategy.RequestedToCapacityRatio == nil {
+			return fmt.Errorf("requestedToCapacityRatio must be configured when type is %q", config.RequestedToCapacityRatio)
+		}
+		if err := validateShape(strategy.RequestedToCapacityRatio.Shape); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid scoring strategy type, got %q", strategy.Type)
+	}
+
+	return validateResourceWeights(strategy.Resources)
+}
+
+func validateResourceWeights(resources []schedulerconfig.ResourceSpec) error {
+	for _, resource := range resources {
+		if resource.Weight <= 0 {
+			return fmt.Errorf("resource Weight of %v should be a positive value, got %v", resource.Name, resource.Weight)
+		}
+	}
+	return nil
+}
+
+// validateShape checks that shape points are sorted by increasing utilization
+// and that utilization and score fall within their valid ranges.
+func validateShape(shape []config.UtilizationShapePoint) error {
+	if len(shape) == 0 {
+		return fmt.Errorf("shape must not be empty when mode is %q", config.Shape)
+	}
+
+	var lastUtilization int32 = -1
+	for i, point := range shape {
+		if point.Utilization < 0 || point.Utilization > 100 {
+			return fmt.Errorf("utilization values must be within 0 and 100, but got %v at shape[%d]", point.Utilization, i)
+		}
+		if point.Score < 0 || int64(point.Score) > framework.MaxNodeScore {
+			return fmt.Errorf("score values must be within 0 and %v, but got %v at shape[%d]", framework.MaxNodeScore, point.Score, i)
+		}
+		if point.Utilization <= lastUtilization {
+			return fmt.Errorf("utilization values must be sorted in increasing order, got %v at shape[%d] after %v", point.Utilization, i, lastUtilization)
+		}
+		lastUtilization = point.Utilization
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"testing"
+
+	schedulerconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+)
+
+func TestValidateNodeResourcesAllocatableArgs(t *testing.T) {
+	validResources := []schedulerconfig.ResourceSpec{{Name: "cpu", Weight: 1}}
+	validShape := []config.UtilizationShapePoint{{Utilization: 0, Score: 10}, {Utilization: 100, Score: 0}}
+
+	tests := []struct {
+		name    string
+		args    *config.NodeResourcesAllocatableArgs
+		wantErr bool
+	}{
+		{
+			name: "deprecated least mode",
+			args: &config.NodeResourcesAllocatableArgs{Mode: config.Least, Resources: validResources},
+		},
+		{
+			name: "deprecated shape mode",
+			args: &config.NodeResourcesAllocatableArgs{Mode: config.Shape, Shape: validShape},
+		},
+		{
+			name:    "deprecated shape mode without shape",
+			args:    &config.NodeResourcesAllocatableArgs{Mode: config.Shape},
+			wantErr: true,
+		},
+		{
+			name: "scoringStrategy least allocated",
+			args: &config.NodeResourcesAllocatableArgs{
+				ScoringStrategy: &config.ScoringStrategy{Type: config.LeastAllocated, Resources: validResources},
+			},
+		},
+		{
+			name: "scoringStrategy requestedToCapacityRatio",
+			args: &config.NodeResourcesAllocatableArgs{
+				ScoringStrategy: &config.ScoringStrategy{
+					Type:                     config.RequestedToCapacityRatio,
+					RequestedToCapacityRatio: &config.RequestedToCapacityRatioParam{Shape: validShape},
+				},
+			},
+		},
+		{
+			name: "scoringStrategy requestedToCapacityRatio missing param",
+			args: &config.NodeResourcesAllocatableArgs{
+				ScoringStrategy: &config.ScoringStrategy{Type: config.RequestedToCapacityRatio},
+			},
+			wantErr: true,
+		},
+		{
+			name: "scoringStrategy mixed with deprecated mode",
+			args: &config.NodeResourcesAllocatableArgs{
+				Mode:            config.Least,
+				ScoringStrategy: &config.ScoringStrategy{Type: config.LeastAllocated},
+			},
+			wantErr: true,
+		},
+		{
+			name: "scoringStrategy mixed with deprecated resources",
+			args: &config.NodeResourcesAllocatableArgs{
+				Resources:       validResources,
+				ScoringStrategy: &config.ScoringStrategy{Type: config.LeastAllocated},
+			},
+			wantErr: true,
+		},
+		{
+			name: "non-positive resource weight",
+			args: &config.NodeResourcesAllocatableArgs{
+				Mode:      config.Least,
+				Resources: []schedulerconfig.ResourceSpec{{Name: "cpu", Weight: 0}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "invalid mode",
+			args:    &config.NodeResourcesAllocatableArgs{Mode: "bogus"},
+			wantErr: true,
+		},
+		{
+			name: "valid volumes",
+			args: &config.NodeResourcesAllocatableArgs{
+				Mode:    config.Least,
+				Volumes: []config.VolumeResourceSpec{{StorageClass: "fast-ssd", Weight: 1}},
+			},
+		},
+		{
+			name: "non-positive volume weight",
+			args: &config.NodeResourcesAllocatableArgs{
+				Mode:    config.Least,
+				Volumes: []config.VolumeResourceSpec{{StorageClass: "fast-ssd", Weight: 0}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateNodeResourcesAllocatableArgs(tt.args, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateNodeResourcesAllocatableArgs(%+v) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+			}
+		})
+	}
+}
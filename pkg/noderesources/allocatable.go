@@ -20,9 +20,16 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"sort"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	storagelisters "k8s.io/client-go/listers/storage/v1"
 	"k8s.io/klog/v2"
 	schedulerconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
@@ -36,10 +43,53 @@ import (
 type Allocatable struct {
 	logger klog.Logger
 	handle framework.Handle
+	mode   config.ModeType
+
+	// volumeWeightMap holds the configured storage classes, keyed by
+	// StorageClass name. Empty unless NodeResourcesAllocatableArgs.Volumes
+	// was configured.
+	volumeWeightMap          map[string]int64
+	pvcLister                corelisters.PersistentVolumeClaimLister
+	csiStorageCapacityLister storagelisters.CSIStorageCapacityLister
+
 	resourceAllocationScorer
 }
 
 var _ = framework.ScorePlugin(&Allocatable{})
+var _ = framework.PreScorePlugin(&Allocatable{})
+
+// preScoreStateKey is the key under which PreScore stores podRequestsStateData.
+const preScoreStateKey = "PreScore-" + AllocatableName
+
+// podRequestsStateData caches the candidate pod's own resource requests
+// across the Score calls for all nodes in a scheduling cycle, so they don't
+// need to be recomputed for every node.
+type podRequestsStateData struct {
+	podRequests resourceToValueMap
+}
+
+// Clone implements framework.StateData.
+func (d *podRequestsStateData) Clone() framework.StateData {
+	return d
+}
+
+// podRequestsFromCycleState reads back the podRequestsStateData written by
+// PreScore, returning a nil map if it was never written, e.g. when the
+// plugin is not running in Balanced or Shape mode.
+func podRequestsFromCycleState(state *framework.CycleState) resourceToValueMap {
+	if state == nil {
+		return nil
+	}
+	c, err := state.Read(preScoreStateKey)
+	if err != nil {
+		return nil
+	}
+	s, ok := c.(*podRequestsStateData)
+	if !ok {
+		return nil
+	}
+	return s.podRequests
+}
 
 // AllocatableName is the name of the plugin used in the Registry and configurations.
 const AllocatableName = "NodeResourcesAllocatable"
@@ -62,16 +112,108 @@ func validateResources(resources []schedulerconfig.ResourceSpec) error {
 // Score invoked at the score extension point.
 func (alloc *Allocatable) Score(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodeName string) (int64, *framework.Status) {
 	logger := klog.FromContext(klog.NewContext(ctx, alloc.logger)).WithValues("ExtensionPoint", "Score")
+
+	start := time.Now()
+	defer func() {
+		scoreDurationMetric.WithLabelValues(alloc.Name()).Observe(time.Since(start).Seconds())
+	}()
+
 	nodeInfo, err := alloc.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
 	if err != nil {
 		return 0, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
 	}
 
+	var volumeAllocatable, volumeRequested resourceToValueMap
+	if len(alloc.volumeWeightMap) > 0 {
+		volumeAllocatable, volumeRequested, err = alloc.classResourceMap(pod, nodeInfo.Node())
+		if err != nil {
+			return 0, framework.NewStatus(framework.Error, fmt.Sprintf("computing volume capacity for node %q: %v", nodeName, err))
+		}
+	}
+
 	// alloc.score favors nodes with least allocatable or most allocatable resources.
 	// It calculates the sum of the node's weighted allocatable resources.
 	//
 	// Note: the returned "score" is negative for least allocatable, and positive for most allocatable.
-	return alloc.score(logger, pod, nodeInfo)
+	return alloc.score(logger, state, pod, nodeInfo, volumeAllocatable, volumeRequested)
+}
+
+// volumeResourceName builds the synthetic resource name under which a
+// storage class's CSI-reported capacity is tracked alongside the node's
+// compute resources.
+func volumeResourceName(storageClass string) v1.ResourceName {
+	return v1.ResourceName("storageclass.storage.k8s.io/" + storageClass)
+}
+
+// classResourceMap returns, for each configured storage class, the capacity
+// a CSI driver advertises as available on node, and the amount pod's own
+// PVCs would request from that class. A storage class missing from the
+// returned allocatable map means no driver reported capacity for it on this
+// node, and callers should treat it as absent rather than scoring it as 0.
+func (alloc *Allocatable) classResourceMap(pod *v1.Pod, node *v1.Node) (allocatable, requested resourceToValueMap, err error) {
+	requested = make(resourceToValueMap, len(alloc.volumeWeightMap))
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		pvc, err := alloc.pvcLister.PersistentVolumeClaims(pod.Namespace).Get(volume.PersistentVolumeClaim.ClaimName)
+		if err != nil {
+			// The PVC may not exist yet (e.g. it's created alongside the pod);
+			// skip it rather than fail scoring.
+			continue
+		}
+		if pvc.Spec.StorageClassName == nil {
+			continue
+		}
+		class := *pvc.Spec.StorageClassName
+		if _, configured := alloc.volumeWeightMap[class]; !configured {
+			continue
+		}
+		if size, ok := pvc.Spec.Resources.Requests[v1.ResourceStorage]; ok {
+			requested[volumeResourceName(class)] += size.Value()
+		}
+	}
+
+	capacities, err := alloc.csiStorageCapacityLister.List(labels.Everything())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	allocatable = make(resourceToValueMap, len(alloc.volumeWeightMap))
+	for class := range alloc.volumeWeightMap {
+		capacity, found := sumCSICapacityForClass(capacities, class, node)
+		if found {
+			allocatable[volumeResourceName(class)] = capacity
+		}
+	}
+
+	return allocatable, requested, nil
+}
+
+// sumCSICapacityForClass returns the total capacity CSI drivers report for
+// class on node, summed across every CSIStorageCapacity object whose
+// topology includes it. A nil NodeTopology means the capacity applies
+// cluster-wide. Multiple objects can legitimately match the same node, e.g.
+// one driver publishing cluster-wide capacity alongside another publishing
+// a zone-scoped topology, so the node's available capacity is the sum of
+// what all of them report rather than any single one.
+func sumCSICapacityForClass(capacities []*storagev1.CSIStorageCapacity, class string, node *v1.Node) (int64, bool) {
+	var total int64
+	var found bool
+	for _, capacity := range capacities {
+		if capacity.StorageClassName != class || capacity.Capacity == nil {
+			continue
+		}
+		if capacity.NodeTopology != nil {
+			selector, err := metav1.LabelSelectorAsSelector(capacity.NodeTopology)
+			if err != nil || !selector.Matches(labels.Set(node.Labels)) {
+				continue
+			}
+		}
+		total += capacity.Capacity.Value()
+		found = true
+	}
+	return total, found
 }
 
 // ScoreExtensions of the Score plugin.
@@ -79,12 +221,31 @@ func (alloc *Allocatable) ScoreExtensions() framework.ScoreExtensions {
 	return alloc
 }
 
+// PreScore caches the candidate pod's own resource requests in state, for
+// Balanced and Shape modes to fold into the node's already-Requested
+// resources: both need to score the node as though the pod were already
+// placed on it, unlike Least/Most which only compare allocatable amounts.
+func (alloc *Allocatable) PreScore(ctx context.Context, state *framework.CycleState, pod *v1.Pod, nodes []*v1.Node) *framework.Status {
+	if alloc.mode != config.Balanced && alloc.mode != config.Shape {
+		return nil
+	}
+
+	podRequests := make(resourceToValueMap, len(alloc.resourceToWeightMap))
+	for resourceName := range alloc.resourceToWeightMap {
+		podRequests[resourceName] = calculatePodResourceRequest(pod, resourceName)
+	}
+	state.Write(preScoreStateKey, &podRequestsStateData{podRequests: podRequests})
+	return nil
+}
+
 // NewAllocatable initializes a new plugin and returns it.
 func NewAllocatable(ctx context.Context, allocArgs runtime.Object, h framework.Handle) (framework.Plugin, error) {
+	registerMetrics()
+
 	logger := klog.FromContext(ctx).WithValues("plugin", AllocatableName)
-	// Start with default values.
-	var mode config.ModeType
-	resToWeightMap := defaultResourcesToWeightMap
+	// Start with the default strategy.
+	strategy := &config.ScoringStrategy{Type: config.LeastAllocated}
+	var volumes []config.VolumeResourceSpec
 
 	// Update values from args, if specified.
 	if allocArgs != nil {
@@ -92,59 +253,223 @@ func NewAllocatable(ctx context.Context, allocArgs runtime.Object, h framework.H
 		if !ok {
 			return nil, fmt.Errorf("want args to be of type NodeResourcesAllocatableArgs, got %T", allocArgs)
 		}
-		if args.Mode == "" {
+		if args.ScoringStrategy == nil && args.Mode == "" {
 			args.Mode = config.Least
 		}
+		if args.ScoringStrategy != nil && args.ScoringStrategy.Type == "" {
+			args.ScoringStrategy.Type = config.LeastAllocated
+		}
 		if err := validation.ValidateNodeResourcesAllocatableArgs(args, nil); err != nil {
 			return nil, err
 		}
-		if len(args.Resources) > 0 {
-			resToWeightMap = make(resourceToWeightMap)
-			for _, resource := range args.Resources {
-				resToWeightMap[v1.ResourceName(resource.Name)] = resource.Weight
+		// ScoringStrategy is the successor of Mode/Resources/Shape; validation
+		// above guarantees they are never both set.
+		if args.ScoringStrategy != nil {
+			strategy = args.ScoringStrategy
+		} else {
+			strategy = config.ConvertToScoringStrategy(args)
+		}
+		volumes = args.Volumes
+	}
+
+	resToWeightMap := defaultResourcesToWeightMap
+	if len(strategy.Resources) > 0 {
+		resToWeightMap = make(resourceToWeightMap)
+		for _, resource := range strategy.Resources {
+			resToWeightMap[v1.ResourceName(resource.Name)] = resource.Weight
+		}
+	}
+
+	var volumeWeightMap map[string]int64
+	if len(volumes) > 0 {
+		if len(strategy.Resources) == 0 {
+			resToWeightMap = make(resourceToWeightMap, len(resToWeightMap))
+			for resource, weight := range defaultResourcesToWeightMap {
+				resToWeightMap[resource] = weight
 			}
 		}
-		mode = args.Mode
+		volumeWeightMap = make(map[string]int64, len(volumes))
+		for _, volume := range volumes {
+			resToWeightMap[volumeResourceName(volume.StorageClass)] = volume.Weight
+			volumeWeightMap[volume.StorageClass] = volume.Weight
+		}
+	}
+
+	mode := config.ModeForScoringStrategyType(strategy.Type)
+	var shape []config.UtilizationShapePoint
+	if strategy.RequestedToCapacityRatio != nil {
+		shape = strategy.RequestedToCapacityRatio.Shape
+	}
+
+	scorer := resourceScorer(logger, resToWeightMap, mode, shape)
+	if mode == config.Balanced {
+		scorer = balancedResourceScorer(logger, resToWeightMap)
 	}
 
 	return &Allocatable{
-		logger: logger,
-		handle: h,
+		logger:                   logger,
+		handle:                   h,
+		mode:                     mode,
+		volumeWeightMap:          volumeWeightMap,
+		pvcLister:                h.SharedInformerFactory().Core().V1().PersistentVolumeClaims().Lister(),
+		csiStorageCapacityLister: h.SharedInformerFactory().Storage().V1().CSIStorageCapacities().Lister(),
 		resourceAllocationScorer: resourceAllocationScorer{
 			Name:                AllocatableName,
-			scorer:              resourceScorer(logger, resToWeightMap, mode),
+			scorer:              scorer,
 			resourceToWeightMap: resToWeightMap,
 		},
 	}, nil
 }
 
-func resourceScorer(logger klog.Logger, resToWeightMap resourceToWeightMap, mode config.ModeType) func(resourceToValueMap, resourceToValueMap) int64 {
-	return func(requested, allocable resourceToValueMap) int64 {
-		// TODO: consider volumes in scoring.
+func resourceScorer(logger klog.Logger, resToWeightMap resourceToWeightMap, mode config.ModeType, shape []config.UtilizationShapePoint) func(string, resourceToValueMap, resourceToValueMap) int64 {
+	return func(nodeName string, requested, allocable resourceToValueMap) int64 {
 		var nodeScore, weightSum int64
+		perResource := make(map[string]int64, len(resToWeightMap))
 		for resource, weight := range resToWeightMap {
-			resourceScore := score(logger, allocable[resource], mode)
+			capacity, ok := allocable[resource]
+			if !ok {
+				// No data for this resource on this node, e.g. no CSI driver
+				// reported capacity for a configured storage class: skip it
+				// rather than scoring it as 0.
+				continue
+			}
+			resourceScore := score(logger, requested[resource], capacity, mode, shape)
+			perResource[string(resource)] = resourceScore
 			nodeScore += resourceScore * weight
 			weightSum += weight
 		}
-		return nodeScore / weightSum
+		if weightSum != 0 {
+			nodeScore /= weightSum
+		}
+		logger.V(6).Info("score breakdown", "node", nodeName, "perResource", perResource, "weightSum", weightSum)
+		return nodeScore
 	}
 }
 
-func score(logger klog.Logger, capacity int64, mode config.ModeType) int64 {
+func score(logger klog.Logger, requested, capacity int64, mode config.ModeType, shape []config.UtilizationShapePoint) int64 {
 	switch mode {
 	case config.Least:
 		return -1 * capacity
 	case config.Most:
 		return capacity
+	case config.Shape:
+		return scoreShape(requested, capacity, shape)
 	}
 
 	logger.V(10).Info("No match for mode", "mode", mode)
 	return 0
 }
 
+// scoreShape maps a resource's utilization (0-100, the percentage of its
+// allocatable amount that is requested) onto the user-configured
+// piecewise-linear utility curve, linearly interpolating between the two
+// points enclosing it. Utilization outside the configured range clamps to
+// the nearest endpoint's score. The direction is fixed to "percentage
+// requested", matching upstream RequestedToCapacityRatio; there's no
+// headroom-direction mode, so a spreading curve must be expressed by
+// inverting the configured Shape's Utilization points instead.
+func scoreShape(requested, capacity int64, shape []config.UtilizationShapePoint) int64 {
+	if capacity == 0 || len(shape) == 0 {
+		return 0
+	}
+
+	utilization := 100 * requested / capacity
+	if first := shape[0]; utilization <= int64(first.Utilization) {
+		return int64(first.Score)
+	}
+	if last := shape[len(shape)-1]; utilization >= int64(last.Utilization) {
+		return int64(last.Score)
+	}
+
+	// shape[i] is the first point whose Utilization exceeds utilization, so
+	// the enclosing segment is (shape[i-1], shape[i]).
+	i := sort.Search(len(shape), func(i int) bool {
+		return int64(shape[i].Utilization) > utilization
+	})
+	lower, upper := shape[i-1], shape[i]
+
+	utilizationRange := int64(upper.Utilization - lower.Utilization)
+	scoreRange := int64(upper.Score - lower.Score)
+	return int64(lower.Score) + (utilization-int64(lower.Utilization))*scoreRange/utilizationRange
+}
+
+// balancedResourceScorer scores a node by how evenly the configured
+// resources' requested-to-allocatable fractions are balanced: the more even
+// the spread, the higher the score. Unlike Least/Most/Shape, this mode
+// considers all configured resources together rather than scoring each in
+// isolation, so it already returns a value in [MinNodeScore, MaxNodeScore].
+// Unlike upstream NodeResourcesBalancedAllocation, each resource's
+// contribution to the mean and variance is weighted by its configured
+// Weight, so e.g. a heavily-weighted extended resource's imbalance counts
+// for more than a lightly-weighted one.
+func balancedResourceScorer(logger klog.Logger, resToWeightMap resourceToWeightMap) func(string, resourceToValueMap, resourceToValueMap) int64 {
+	return func(nodeName string, requested, allocable resourceToValueMap) int64 {
+		type weightedFraction struct {
+			fraction float64
+			weight   float64
+		}
+		fractions := make([]weightedFraction, 0, len(resToWeightMap))
+		perResource := make(map[string]int64, len(resToWeightMap))
+		for resource, weight := range resToWeightMap {
+			capacity := allocable[resource]
+			if capacity == 0 {
+				continue
+			}
+			fraction := float64(requested[resource]) / float64(capacity)
+			fractions = append(fractions, weightedFraction{fraction: fraction, weight: float64(weight)})
+			resourceScore := int64(fraction * float64(framework.MaxNodeScore))
+			perResource[string(resource)] = resourceScore
+		}
+		if len(fractions) < 2 {
+			logger.V(6).Info("score breakdown", "node", nodeName, "perResource", perResource, "weightSum", int64(0))
+			return framework.MinNodeScore
+		}
+
+		var weightSum float64
+		for _, wf := range fractions {
+			weightSum += wf.weight
+		}
+
+		var mean float64
+		for _, wf := range fractions {
+			mean += wf.fraction * wf.weight
+		}
+		mean /= weightSum
+
+		var variance float64
+		for _, wf := range fractions {
+			variance += wf.weight * (wf.fraction - mean) * (wf.fraction - mean)
+		}
+		variance /= weightSum
+
+		nodeScore := int64((1 - math.Sqrt(variance)) * float64(framework.MaxNodeScore))
+		logger.V(6).Info("score breakdown", "node", nodeName, "perResource", perResource, "weightSum", int64(weightSum))
+		// A fraction can exceed 1 (e.g. an over-committed node, or this
+		// plugin run without a Fit filter gating it), pushing the stddev
+		// above 1 and nodeScore below MinNodeScore. Clamp rather than
+		// returning an out-of-range score, which the framework rejects.
+		if nodeScore < framework.MinNodeScore {
+			return framework.MinNodeScore
+		}
+		if nodeScore > framework.MaxNodeScore {
+			return framework.MaxNodeScore
+		}
+		return nodeScore
+	}
+}
+
 // NormalizeScore invoked after scoring all nodes.
 func (alloc *Allocatable) NormalizeScore(ctx context.Context, state *framework.CycleState, pod *v1.Pod, scores framework.NodeScoreList) *framework.Status {
+	// Balanced and Shape already score directly in [MinNodeScore,
+	// MaxNodeScore] — Balanced from its stddev formula, Shape from the
+	// user-configured utility curve's own Score points, the same as
+	// upstream RequestedToCapacityRatio. A second min-max normalization
+	// pass would discard the absolute magnitude either was written to
+	// express and distort its weight relative to other plugins.
+	if alloc.mode == config.Balanced || alloc.mode == config.Shape {
+		return nil
+	}
+
 	// Find highest and lowest scores.
 	var highest int64 = -math.MaxInt64
 	var lowest int64 = math.MaxInt64
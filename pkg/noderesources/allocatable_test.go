@@ -0,0 +1,231 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesources
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/scheduler-plugins/apis/config"
+)
+
+func TestScoreShape(t *testing.T) {
+	binPacking := []config.UtilizationShapePoint{
+		{Utilization: 0, Score: 0},
+		{Utilization: 100, Score: 10},
+	}
+	spreading := []config.UtilizationShapePoint{
+		{Utilization: 0, Score: 10},
+		{Utilization: 100, Score: 0},
+	}
+	preferMidRange := []config.UtilizationShapePoint{
+		{Utilization: 0, Score: 2},
+		{Utilization: 60, Score: 10},
+		{Utilization: 80, Score: 10},
+		{Utilization: 100, Score: 0},
+	}
+	singlePoint := []config.UtilizationShapePoint{
+		{Utilization: 50, Score: 7},
+	}
+
+	tests := []struct {
+		name      string
+		requested int64
+		capacity  int64
+		shape     []config.UtilizationShapePoint
+		want      int64
+	}{
+		{name: "monotonic increasing, 0%", requested: 0, capacity: 100, shape: binPacking, want: 0},
+		{name: "monotonic increasing, 50%", requested: 50, capacity: 100, shape: binPacking, want: 5},
+		{name: "monotonic increasing, 100%", requested: 100, capacity: 100, shape: binPacking, want: 10},
+		{name: "monotonic decreasing, 25%", requested: 25, capacity: 100, shape: spreading, want: 8},
+		{name: "non-monotonic, below first segment", requested: 30, capacity: 100, shape: preferMidRange, want: 6},
+		{name: "non-monotonic, in flat segment", requested: 70, capacity: 100, shape: preferMidRange, want: 10},
+		{name: "non-monotonic, in final segment", requested: 90, capacity: 100, shape: preferMidRange, want: 5},
+		{name: "utilization below first point clamps", requested: 0, capacity: 100, shape: singlePoint, want: 7},
+		{name: "utilization above last point clamps", requested: 100, capacity: 100, shape: singlePoint, want: 7},
+		{name: "zero capacity", requested: 0, capacity: 0, shape: binPacking, want: 0},
+		{name: "no shape configured", requested: 50, capacity: 100, shape: nil, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scoreShape(tt.requested, tt.capacity, tt.shape); got != tt.want {
+				t.Errorf("scoreShape(%d, %d, %v) = %d, want %d", tt.requested, tt.capacity, tt.shape, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBalancedResourceScorer(t *testing.T) {
+	cpuMem := resourceToWeightMap{v1.ResourceCPU: 1, v1.ResourceMemory: 1}
+	cpuMemGPU := resourceToWeightMap{v1.ResourceCPU: 1, v1.ResourceMemory: 1, "nvidia.com/gpu": 1}
+	cpuMemHeavyGPU := resourceToWeightMap{v1.ResourceCPU: 1, v1.ResourceMemory: 1, "nvidia.com/gpu": 8}
+
+	tests := []struct {
+		name      string
+		resources resourceToWeightMap
+		requested resourceToValueMap
+		allocable resourceToValueMap
+		want      int64
+	}{
+		{
+			name:      "cpu/mem perfectly balanced",
+			resources: cpuMem,
+			requested: resourceToValueMap{v1.ResourceCPU: 50, v1.ResourceMemory: 50},
+			allocable: resourceToValueMap{v1.ResourceCPU: 100, v1.ResourceMemory: 100},
+			want:      100,
+		},
+		{
+			name:      "cpu/mem unbalanced",
+			resources: cpuMem,
+			requested: resourceToValueMap{v1.ResourceCPU: 20, v1.ResourceMemory: 80},
+			allocable: resourceToValueMap{v1.ResourceCPU: 100, v1.ResourceMemory: 100},
+			want:      70,
+		},
+		{
+			name:      "gpu included",
+			resources: cpuMemGPU,
+			requested: resourceToValueMap{v1.ResourceCPU: 40, v1.ResourceMemory: 60, "nvidia.com/gpu": 50},
+			allocable: resourceToValueMap{v1.ResourceCPU: 100, v1.ResourceMemory: 100, "nvidia.com/gpu": 100},
+			want:      91,
+		},
+		{
+			name:      "pod requests nothing",
+			resources: cpuMem,
+			requested: resourceToValueMap{v1.ResourceCPU: 0, v1.ResourceMemory: 0},
+			allocable: resourceToValueMap{v1.ResourceCPU: 100, v1.ResourceMemory: 100},
+			want:      100,
+		},
+		{
+			name:      "fewer than two usable resources falls back to MinNodeScore",
+			resources: cpuMem,
+			requested: resourceToValueMap{v1.ResourceCPU: 50, v1.ResourceMemory: 0},
+			allocable: resourceToValueMap{v1.ResourceCPU: 100, v1.ResourceMemory: 0},
+			want:      0,
+		},
+		{
+			name:      "over-committed node clamps to MinNodeScore",
+			resources: cpuMem,
+			requested: resourceToValueMap{v1.ResourceCPU: 300, v1.ResourceMemory: 0},
+			allocable: resourceToValueMap{v1.ResourceCPU: 100, v1.ResourceMemory: 100},
+			want:      0,
+		},
+		{
+			// A heavily-weighted, perfectly-idle GPU pulls the weighted mean
+			// and variance toward it, yielding a different score than an
+			// unweighted average of the same fractions would (80 vs. 76).
+			name:      "heavily weighted resource dominates the balance",
+			resources: cpuMemHeavyGPU,
+			requested: resourceToValueMap{v1.ResourceCPU: 50, v1.ResourceMemory: 50, "nvidia.com/gpu": 0},
+			allocable: resourceToValueMap{v1.ResourceCPU: 100, v1.ResourceMemory: 100, "nvidia.com/gpu": 100},
+			want:      80,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scorer := balancedResourceScorer(klog.Background(), tt.resources)
+			if got := scorer("node-a", tt.requested, tt.allocable); got != tt.want {
+				t.Errorf("balancedResourceScorer(...)(%v, %v) = %d, want %d", tt.requested, tt.allocable, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSumCSICapacityForClass(t *testing.T) {
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a", Labels: map[string]string{"zone": "a"}}}
+
+	capacity := func(class, zone string, gib int64) *storagev1.CSIStorageCapacity {
+		c := &storagev1.CSIStorageCapacity{
+			StorageClassName: class,
+			Capacity:         resource.NewQuantity(gib<<30, resource.BinarySI),
+		}
+		if zone != "" {
+			c.NodeTopology = &metav1.LabelSelector{MatchLabels: map[string]string{"zone": zone}}
+		}
+		return c
+	}
+
+	tests := []struct {
+		name       string
+		capacities []*storagev1.CSIStorageCapacity
+		class      string
+		wantFound  bool
+		wantGiB    int64
+	}{
+		{
+			name:       "cluster-wide capacity matches",
+			capacities: []*storagev1.CSIStorageCapacity{capacity("fast-ssd", "", 100)},
+			class:      "fast-ssd",
+			wantFound:  true,
+			wantGiB:    100,
+		},
+		{
+			name:       "zone-scoped capacity matches node's zone",
+			capacities: []*storagev1.CSIStorageCapacity{capacity("fast-ssd", "a", 50)},
+			class:      "fast-ssd",
+			wantFound:  true,
+			wantGiB:    50,
+		},
+		{
+			name:       "zone-scoped capacity does not match other zone",
+			capacities: []*storagev1.CSIStorageCapacity{capacity("fast-ssd", "b", 50)},
+			class:      "fast-ssd",
+			wantFound:  false,
+		},
+		{
+			name:       "unrelated storage class ignored",
+			capacities: []*storagev1.CSIStorageCapacity{capacity("slow-hdd", "", 200)},
+			class:      "fast-ssd",
+			wantFound:  false,
+		},
+		{
+			name: "multiple matches are summed",
+			capacities: []*storagev1.CSIStorageCapacity{
+				capacity("fast-ssd", "a", 80),
+				capacity("fast-ssd", "", 30),
+			},
+			class:     "fast-ssd",
+			wantFound: true,
+			wantGiB:   110,
+		},
+		{
+			name:       "no driver reports the class",
+			capacities: nil,
+			class:      "fast-ssd",
+			wantFound:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := sumCSICapacityForClass(tt.capacities, tt.class, node)
+			if found != tt.wantFound {
+				t.Fatalf("sumCSICapacityForClass(...) found = %v, want %v", found, tt.wantFound)
+			}
+			if found && got != tt.wantGiB<<30 {
+				t.Errorf("sumCSICapacityForClass(...) = %d, want %d", got, tt.wantGiB<<30)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesources
+
+import (
+	"sync"
+
+	compbasemetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const metricsSubsystem = "scheduler_plugin_noderesources"
+
+var (
+	// scoreDurationMetric tracks how long a single Score call takes.
+	//
+	// A per-resource score metric was tried here too, but per-resource
+	// scores range from 0-100 utility points (Shape, Balanced) to raw
+	// allocatable quantities in arbitrary units (Least, Most), and a gauge
+	// keyed without "node" (to avoid unbounded per-node series) would have
+	// each node's Set silently overwrite the last, leaving a scrape showing
+	// only whichever node was scored last. The "score breakdown" V(6) log
+	// line in resourceScorer/balancedResourceScorer covers the same
+	// debugging need without either problem.
+	scoreDurationMetric = compbasemetrics.NewHistogramVec(
+		&compbasemetrics.HistogramOpts{
+			Subsystem:      metricsSubsystem,
+			Name:           "score_duration_seconds",
+			Help:           "Duration in seconds of a single NodeResourcesAllocatable Score call.",
+			Buckets:        compbasemetrics.DefBuckets,
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"plugin"},
+	)
+
+	registerMetricsOnce sync.Once
+)
+
+// registerMetrics registers the noderesources metrics with the legacy
+// registry exactly once, so instantiating the plugin in multiple scheduler
+// profiles doesn't panic on duplicate registration.
+func registerMetrics() {
+	registerMetricsOnce.Do(func() {
+		legacyregistry.MustRegister(scoreDurationMetric)
+	})
+}
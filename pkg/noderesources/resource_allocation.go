@@ -0,0 +1,111 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesources
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	resourcehelper "k8s.io/kubernetes/pkg/api/v1/resource"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+// resourceToWeightMap contains resource name and weight.
+type resourceToWeightMap map[v1.ResourceName]int64
+
+// resourceToValueMap contains resource name and value.
+type resourceToValueMap map[v1.ResourceName]int64
+
+// defaultResourcesToWeightMap is used when the user does not configure a
+// custom set of resources to score.
+var defaultResourcesToWeightMap = resourceToWeightMap{
+	v1.ResourceCPU:    1,
+	v1.ResourceMemory: 1,
+}
+
+// resourceAllocationScorer contains information to calculate resource allocation score.
+type resourceAllocationScorer struct {
+	Name                string
+	scorer              func(nodeName string, requested, allocable resourceToValueMap) int64
+	resourceToWeightMap resourceToWeightMap
+}
+
+// score will use `scorer` function to calculate the score. volumeAllocatable
+// and volumeRequested carry the per-node, per-storage-class view built by
+// Allocatable.classResourceMap for any resource names r.resourceToWeightMap
+// doesn't already know how to read off nodeInfo; a storage class missing
+// from volumeAllocatable is left out of the maps passed to scorer entirely,
+// so it's excluded rather than scored as 0.
+func (r *resourceAllocationScorer) score(logger klog.Logger, state *framework.CycleState, pod *v1.Pod, nodeInfo *framework.NodeInfo, volumeAllocatable, volumeRequested resourceToValueMap) (int64, *framework.Status) {
+	if r.resourceToWeightMap == nil {
+		return 0, framework.NewStatus(framework.Error, "resources not found")
+	}
+
+	// podRequests is non-empty only when a PreScore stage cached the
+	// candidate pod's own requests, e.g. for the Balanced and Shape modes,
+	// which need to score as though the pod were already placed on the node.
+	podRequests := podRequestsFromCycleState(state)
+
+	requested := make(resourceToValueMap, len(r.resourceToWeightMap))
+	allocatable := make(resourceToValueMap, len(r.resourceToWeightMap))
+	for resourceName := range r.resourceToWeightMap {
+		if alloc, req, ok := calculateResourceAllocatableRequest(nodeInfo, resourceName); ok {
+			allocatable[resourceName] = alloc
+			requested[resourceName] = req + podRequests[resourceName]
+			continue
+		}
+		if alloc, ok := volumeAllocatable[resourceName]; ok {
+			allocatable[resourceName] = alloc
+			requested[resourceName] = volumeRequested[resourceName]
+		}
+	}
+
+	return r.scorer(nodeInfo.Node().Name, requested, allocatable), nil
+}
+
+// calculateResourceAllocatableRequest returns resourceAllocatable and
+// resourceRequest for the resourceName, as tracked by nodeInfo, and whether
+// nodeInfo tracks that resource at all.
+func calculateResourceAllocatableRequest(nodeInfo *framework.NodeInfo, resourceName v1.ResourceName) (int64, int64, bool) {
+	switch resourceName {
+	case v1.ResourceCPU:
+		return nodeInfo.Allocatable.MilliCPU, nodeInfo.Requested.MilliCPU, true
+	case v1.ResourceMemory:
+		return nodeInfo.Allocatable.Memory, nodeInfo.Requested.Memory, true
+	case v1.ResourceEphemeralStorage:
+		return nodeInfo.Allocatable.EphemeralStorage, nodeInfo.Requested.EphemeralStorage, true
+	default:
+		if _, exists := nodeInfo.Allocatable.ScalarResources[resourceName]; exists {
+			return nodeInfo.Allocatable.ScalarResources[resourceName], nodeInfo.Requested.ScalarResources[resourceName], true
+		}
+	}
+	return 0, 0, false
+}
+
+// calculatePodResourceRequest returns the effective amount of resourceName
+// requested by pod, using the same semantics as the node's own accounting
+// (milli-units for CPU, whole units otherwise).
+func calculatePodResourceRequest(pod *v1.Pod, resourceName v1.ResourceName) int64 {
+	requests := resourcehelper.PodRequests(pod, resourcehelper.PodResourcesOptions{})
+	quantity, ok := requests[resourceName]
+	if !ok {
+		return 0
+	}
+	if resourceName == v1.ResourceCPU {
+		return quantity.MilliValue()
+	}
+	return quantity.Value()
+}